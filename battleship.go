@@ -8,56 +8,11 @@ import (
 	"log"
 	"math/rand"
 	"net/http"
+	_ "net/http/pprof"
 	"strconv"
 	"time"
 )
 
-type Ship interface {
-	Position() (row, col int)
-	Alive() bool
-	TakeHit() bool
-}
-
-type BasicShip struct {
-	Row int
-	Col int
-	hp  int
-}
-
-func NewBasicShip(row, col int) *BasicShip {
-	return &BasicShip{Row: row, Col: col, hp: 1}
-}
-
-func (s *BasicShip) Position() (int, int) { return s.Row, s.Col }
-func (s *BasicShip) Alive() bool          { return s.hp > 0 }
-func (s *BasicShip) TakeHit() bool {
-	if s.hp <= 0 {
-		return true
-	}
-	s.hp--
-	return s.hp <= 0
-}
-
-type ArmoredShip struct {
-	Row   int
-	Col   int
-	Armor int
-}
-
-func NewArmoredShip(row, col, armor int) *ArmoredShip {
-	return &ArmoredShip{Row: row, Col: col, Armor: armor}
-}
-
-func (s *ArmoredShip) Position() (int, int) { return s.Row, s.Col }
-func (s *ArmoredShip) Alive() bool          { return s.Armor > 0 }
-func (s *ArmoredShip) TakeHit() bool {
-	if s.Armor <= 0 {
-		return true
-	}
-	s.Armor--
-	return s.Armor <= 0
-}
-
 type Board struct {
 	Size  int
 	Cells [][]rune
@@ -95,28 +50,25 @@ type Player struct {
 
 type Game struct {
 	Board *Board
-	Ship  Ship
+	Fleet *Fleet
 	Over  bool
 	Shots int
 }
 
 type ShotResult struct {
-	Hit       bool `json:"hit"`
-	Destroyed bool `json:"destroyed"`
+	Hit      bool     `json:"hit"`
+	ShipID   int      `json:"ship_id,omitempty"`
+	ShipType ShipType `json:"ship_type,omitempty"`
+	Sunk     bool     `json:"sunk"`
 }
 
-func NewGame(boardSize int, ship Ship) *Game {
+func NewGame(board *Board, fleet *Fleet) *Game {
 	return &Game{
-		Board: NewBoard(boardSize),
-		Ship:  ship,
+		Board: board,
+		Fleet: fleet,
 	}
 }
 
-func (g *Game) hitAt(r, c int) bool {
-	sr, sc := g.Ship.Position()
-	return r == sr && c == sc && g.Ship.Alive()
-}
-
 func (g *Game) TakeShot(r, c int) (ShotResult, error) {
 	if g.Over {
 		return ShotResult{}, fmt.Errorf("game is allready done")
@@ -125,30 +77,48 @@ func (g *Game) TakeShot(r, c int) (ShotResult, error) {
 		return ShotResult{}, fmt.Errorf("out of range")
 	}
 	g.Shots++
-	sr := ShotResult{Hit: false, Destroyed: false}
-
-	shipR, shipC := g.Ship.Position()
-	if r == shipR && c == shipC && g.Ship.Alive() {
-		sr.Hit = true
-		destroyed := g.Ship.TakeHit()
-		sr.Destroyed = destroyed
-		if destroyed {
+	res := ShotResult{}
+
+	ship, ok := g.Fleet.ShipAt(r, c)
+	if ok {
+		hit, sunk := ship.TakeHit(r, c)
+		res.Hit = hit
+		res.Sunk = sunk
+		res.ShipID = ship.ID()
+		res.ShipType = ship.Type()
+		if sunk {
 			g.Board.Cells[r][c] = 'X'
-			g.Over = true
 		} else {
 			g.Board.Cells[r][c] = 'H'
 		}
-	} else {
-		if g.Board.Cells[r][c] == '.' {
-			g.Board.Cells[r][c] = 'o'
+		g.Over = !g.Fleet.Alive()
+	} else if g.Board.Cells[r][c] == '.' {
+		g.Board.Cells[r][c] = 'o'
+	}
+	return res, nil
+}
+
+// Render renders the fog-of-war board, or, if reveal is true, also shows the
+// still-afloat ship cells that haven't been shot at yet.
+func (g *Game) Render(reveal bool) string {
+	out := ""
+	for i := 0; i < g.Board.Size; i++ {
+		for j := 0; j < g.Board.Size; j++ {
+			c := g.Board.Cells[i][j]
+			if reveal && c == '.' {
+				if ship, ok := g.Fleet.ShipAt(i, j); ok && ship.Alive() {
+					c = 'S'
+				}
+			}
+			out += fmt.Sprintf("%c ", c)
 		}
 	}
-	return sr, nil
+	return out
 }
 
 func RunConsoleUI(g *Game) {
 	for !g.Over {
-		fmt.Print(g.Board.String())
+		fmt.Print(g.Render(false))
 		var r, c int
 		if _, err := fmt.Scan(&r, &c); err != nil {
 			fmt.Println("error: ", err)
@@ -160,21 +130,72 @@ func RunConsoleUI(g *Game) {
 			continue
 		}
 		switch {
-		case res.Destroyed:
-			fmt.Println("Ship destroyed")
+		case res.Sunk:
+			fmt.Printf("%s sunk\n", res.ShipType)
 		case res.Hit:
-			fmt.Println("Hit, but ship is alive")
+			fmt.Printf("Hit on %s\n", res.ShipType)
 		default:
 			fmt.Println("Miss")
 		}
 	}
 	fmt.Println("Final board: ")
-	fmt.Print(g.Board.String())
+	fmt.Print(g.Render(true))
+}
+
+// gameFromRequest resolves the ?id= query parameter against lobby, falling
+// back to defaultID so the old single-game /shot and /board URLs keep
+// working unchanged.
+func gameFromRequest(lobby *Lobby, defaultID string, r *http.Request) (*GameEntry, error) {
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		id = defaultID
+	}
+	entry, ok := lobby.Get(id)
+	if !ok {
+		return nil, fmt.Errorf("no such game: %s", id)
+	}
+	if entry.Stopped {
+		return nil, fmt.Errorf("game %s is stopped", id)
+	}
+	return entry, nil
+}
+
+// newLobby builds the process-wide Lobby, wiring up a FileStore under
+// saveDir so games survive a restart. An empty saveDir runs without
+// persistence, same as NewLobby.
+func newLobby(saveDir string) (*Lobby, error) {
+	if saveDir == "" {
+		return NewLobby(), nil
+	}
+	store, err := NewFileStore(saveDir)
+	if err != nil {
+		return nil, fmt.Errorf("opening save dir: %w", err)
+	}
+	return NewLobbyWithStore(store)
+}
+
+// servePprof starts net/http/pprof's handlers on their own localhost-only
+// port, separate from the game server, so profiling never competes with
+// game traffic on the public listener.
+func servePprof(addr string) {
+	log.Printf("pprof listening on %s", addr)
+	go func() {
+		if err := http.ListenAndServe(addr, nil); err != nil {
+			log.Printf("pprof server: %v", err)
+		}
+	}()
 }
 
-func runHTTP(g *Game) {
+func runHTTP(lobby *Lobby, defaultID, addr string, armor int, turnTimeout time.Duration) {
 	mux := http.NewServeMux()
 	mux.HandleFunc("/shot", func(w http.ResponseWriter, r *http.Request) {
+		entry, err := gameFromRequest(lobby, defaultID, r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		g := entry.Game
+
 		q := r.URL.Query()
 		rStr := q.Get("r")
 		cStr := q.Get("c")
@@ -189,7 +210,7 @@ func runHTTP(g *Game) {
 			return
 		}
 
-		res, err := g.TakeShot(ri, ci)
+		res, err := lobby.TakeShot(entry.ID, ri, ci)
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
@@ -197,9 +218,11 @@ func runHTTP(g *Game) {
 
 		resp := map[string]any{
 			"hit":       res.Hit,
-			"destroyed": res.Destroyed,
+			"ship_id":   res.ShipID,
+			"ship_type": res.ShipType,
+			"sunk":      res.Sunk,
 			"shots":     g.Shots,
-			"board":     g.Board.String(),
+			"board":     g.Render(false),
 			"over":      g.Over,
 		}
 
@@ -208,11 +231,21 @@ func runHTTP(g *Game) {
 	})
 
 	mux.HandleFunc("/board", func(w http.ResponseWriter, r *http.Request) {
+		entry, err := gameFromRequest(lobby, defaultID, r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		reveal := r.URL.Query().Get("reveal") == "true"
 		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
-		_, _ = w.Write([]byte(g.Board.String()))
+		_, _ = w.Write([]byte(entry.Game.Render(reveal)))
 	})
 
-	addr := ":8080"
+	registerLobbyRoutes(mux, lobby)
+
+	defaultEntry, _ := lobby.Get(defaultID)
+	registerMatchRoutes(mux, defaultEntry.Game.Board.Size, armor, turnTimeout)
+
 	log.Fatal(http.ListenAndServe(addr, mux))
 }
 
@@ -220,24 +253,106 @@ func main() {
 	httpMode := flag.Bool("http", false, "http ui mode")
 	armor := flag.Int("armor", 0, "armor count")
 	size := flag.Int("size", 5, "board size")
+	bot := flag.String("bot", "", "play automatically with the named strategy (random, hunt, parity)")
+	botVsBot := flag.Int("bot-vs-bot", 0, "run N headless trials per strategy and print average shots to win, then exit")
+	saveDir := flag.String("save-dir", "", "persist games as JSON under this directory and restore them on startup (disabled if empty)")
+	pruneTTL := flag.Duration("prune-ttl", 24*time.Hour, "delete finished games older than this from --save-dir")
+	pruneInterval := flag.Duration("prune-interval", time.Hour, "how often to check for games to prune")
+	configPath := flag.String("config", "", "path to a JSON config file (defaults to ~/.config/battleship/config.json)")
+	addr := flag.String("addr", ":8080", "http listen address")
+	maxGames := flag.Int("max-games", 0, "reject /game/start once this many games are active (0 = unlimited)")
+	turnTimeout := flag.Duration("turn-timeout", 0, "forfeit a networked match if a player doesn't shoot within this long (0 = disabled)")
+	enablePprof := flag.Bool("pprof", false, "serve net/http/pprof on localhost:6060")
+	logLevel := flag.String("log-level", "info", "log verbosity (info or debug)")
 	flag.Parse()
 
 	rand.Seed(time.Now().UnixNano())
-	row := rand.Intn(*size)
-	col := rand.Intn(*size)
 
-	var ship Ship
-	if *armor > 0 {
-		ship = NewArmoredShip(row, col, *armor)
-	} else {
-		ship = NewBasicShip(row, col)
+	path := *configPath
+	if path == "" {
+		if p, err := DefaultConfigPath(); err == nil {
+			path = p
+		}
+	}
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		log.Fatal(err)
 	}
 
-	game := NewGame(*size, ship)
+	explicit := map[string]bool{}
+	flag.Visit(func(f *flag.Flag) { explicit[f.Name] = true })
+
+	if !explicit["addr"] && cfg.ListenAddr != "" {
+		*addr = cfg.ListenAddr
+	}
+	if !explicit["size"] && cfg.BoardSize > 0 {
+		*size = cfg.BoardSize
+	}
+	if !explicit["armor"] && cfg.ArmorDefaults > 0 {
+		*armor = cfg.ArmorDefaults
+	}
+	if !explicit["max-games"] && cfg.MaxGames > 0 {
+		*maxGames = cfg.MaxGames
+	}
+	if !explicit["turn-timeout"] && cfg.TurnTimeoutSeconds > 0 {
+		*turnTimeout = time.Duration(cfg.TurnTimeoutSeconds) * time.Second
+	}
+	if !explicit["pprof"] && cfg.EnablePprof {
+		*enablePprof = true
+	}
+	if !explicit["log-level"] && cfg.LogLevel != "" {
+		*logLevel = cfg.LogLevel
+	}
+	fleetTypes := defaultFleetTypes
+	if len(cfg.DefaultFleet) > 0 {
+		fleetTypes = cfg.DefaultFleet
+	}
+
+	SetLogLevel(*logLevel)
+	if *enablePprof {
+		servePprof("localhost:6060")
+	}
+
+	if *botVsBot > 0 {
+		runBotBenchmark(*size, *armor, *botVsBot)
+		return
+	}
 
 	if *httpMode {
-		runHTTP(game)
-	} else {
-		RunConsoleUI(game)
+		lobby, err := newLobby(*saveDir)
+		if err != nil {
+			log.Fatal(err)
+		}
+		lobby.MaxGames = *maxGames
+		if *saveDir != "" {
+			go lobby.PruneLoop(*pruneTTL, *pruneInterval, nil)
+		}
+		entry, err := lobby.Start(GameConfig{BoardSize: *size, Armor: *armor, Fleet: fleetTypes})
+		if err != nil {
+			log.Fatal(err)
+		}
+		if *bot != "" {
+			strategy := strategyByName(*bot, minFleetLen(fleetTypes))
+			go NewAIPlayer(*bot, strategy).PlayOutLobby(lobby, entry)
+		}
+		runHTTP(lobby, entry.ID, *addr, *armor, *turnTimeout)
+		return
+	}
+
+	board := NewBoard(*size)
+	fleet, err := NewPlacer(board, *armor).PlaceRandom(fleetTypes)
+	if err != nil {
+		log.Fatal(err)
+	}
+	game := NewGame(board, fleet)
+
+	if *bot != "" {
+		strategy := strategyByName(*bot, minFleetLen(fleetTypes))
+		shots := NewAIPlayer(*bot, strategy).PlayOut(game)
+		fmt.Printf("%s strategy won in %d shots\n", *bot, shots)
+		fmt.Print(game.Render(true))
+		return
 	}
+
+	RunConsoleUI(game)
 }
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// BoardView is the read-only slice of a Board a Strategy is allowed to see:
+// only shot marks, never the live Fleet. It mirrors the fog-of-war board an
+// HTTP client would receive from /board.
+type BoardView struct {
+	Size  int
+	Cells [][]rune
+}
+
+// NewBoardView snapshots b's shot marks for a Strategy to inspect.
+func NewBoardView(b *Board) BoardView {
+	return BoardView{Size: b.Size, Cells: b.Cells}
+}
+
+func (v BoardView) inBounds(r, c int) bool {
+	return r >= 0 && r < v.Size && c >= 0 && c < v.Size
+}
+
+func (v BoardView) tried(r, c int) bool {
+	return v.inBounds(r, c) && v.Cells[r][c] != '.'
+}
+
+// untried returns every cell not yet shot at.
+func (v BoardView) untried() [][2]int {
+	var out [][2]int
+	for r := 0; r < v.Size; r++ {
+		for c := 0; c < v.Size; c++ {
+			if !v.tried(r, c) {
+				out = append(out, [2]int{r, c})
+			}
+		}
+	}
+	return out
+}
+
+// hits returns every cell marked as a hit that hasn't sunk its ship yet.
+func (v BoardView) hits() [][2]int {
+	var out [][2]int
+	for r := 0; r < v.Size; r++ {
+		for c := 0; c < v.Size; c++ {
+			if v.Cells[r][c] == 'H' {
+				out = append(out, [2]int{r, c})
+			}
+		}
+	}
+	return out
+}
+
+// untriedNeighbors returns the orthogonal neighbors of (r, c) that haven't
+// been shot at.
+func (v BoardView) untriedNeighbors(r, c int) [][2]int {
+	var out [][2]int
+	for _, d := range [][2]int{{-1, 0}, {1, 0}, {0, -1}, {0, 1}} {
+		nr, nc := r+d[0], c+d[1]
+		if v.inBounds(nr, nc) && !v.tried(nr, nc) {
+			out = append(out, [2]int{nr, nc})
+		}
+	}
+	return out
+}
+
+func randomFrom(cells [][2]int) (int, int) {
+	pick := cells[rand.Intn(len(cells))]
+	return pick[0], pick[1]
+}
+
+// Strategy picks the next cell an AIPlayer should shoot at, given what it
+// has learned so far from the fog-of-war board.
+type Strategy interface {
+	NextShot(view BoardView) (r, c int)
+}
+
+// RandomStrategy shoots any cell that hasn't been tried yet.
+type RandomStrategy struct{}
+
+func (RandomStrategy) NextShot(view BoardView) (int, int) {
+	return randomFrom(view.untried())
+}
+
+// HuntTargetStrategy shoots at random while "hunting", then switches to
+// probing the untried neighbors of its most recent hit until that ship
+// sinks.
+type HuntTargetStrategy struct{}
+
+func (HuntTargetStrategy) NextShot(view BoardView) (int, int) {
+	for _, hit := range view.hits() {
+		if neighbors := view.untriedNeighbors(hit[0], hit[1]); len(neighbors) > 0 {
+			return randomFrom(neighbors)
+		}
+	}
+	return randomFrom(view.untried())
+}
+
+// ParityStrategy only shoots cells where (r+c)%MinShipLen == 0 until it
+// lands a hit, since every ship of length >= MinShipLen must cross one of
+// those cells; after the first hit it probes like HuntTargetStrategy.
+type ParityStrategy struct {
+	MinShipLen int
+}
+
+func (s ParityStrategy) NextShot(view BoardView) (int, int) {
+	if hits := view.hits(); len(hits) > 0 {
+		return HuntTargetStrategy{}.NextShot(view)
+	}
+
+	minLen := s.MinShipLen
+	if minLen <= 0 {
+		minLen = 1
+	}
+	var parity [][2]int
+	for _, cell := range view.untried() {
+		if (cell[0]+cell[1])%minLen == 0 {
+			parity = append(parity, cell)
+		}
+	}
+	if len(parity) == 0 {
+		return randomFrom(view.untried())
+	}
+	return randomFrom(parity)
+}
+
+// AIPlayer fires shots at a Game using a Strategy until the game ends.
+type AIPlayer struct {
+	Name     string
+	Strategy Strategy
+}
+
+func NewAIPlayer(name string, strategy Strategy) *AIPlayer {
+	return &AIPlayer{Name: name, Strategy: strategy}
+}
+
+// PlayShot asks the strategy for a cell and fires it at g.
+func (a *AIPlayer) PlayShot(g *Game) (r, c int, res ShotResult, err error) {
+	r, c = a.Strategy.NextShot(NewBoardView(g.Board))
+	res, err = g.TakeShot(r, c)
+	return r, c, res, err
+}
+
+// PlayOut fires shots one at a time until g is over, returning the number of
+// shots it took.
+func (a *AIPlayer) PlayOut(g *Game) int {
+	for !g.Over {
+		if _, _, _, err := a.PlayShot(g); err != nil {
+			break
+		}
+	}
+	return g.Shots
+}
+
+// PlayOutLobby fires shots one at a time against a Lobby-managed game until
+// it's over, routing every shot through lobby.TakeShot instead of mutating
+// entry.Game directly. That keeps a bot's shots serialized against
+// concurrent /shot requests for the same game id and snapshotted to the
+// Lobby's Store like any other shot.
+func (a *AIPlayer) PlayOutLobby(lobby *Lobby, entry *GameEntry) int {
+	for !entry.Game.Over {
+		r, c := a.Strategy.NextShot(NewBoardView(entry.Game.Board))
+		if _, err := lobby.TakeShot(entry.ID, r, c); err != nil {
+			break
+		}
+	}
+	return entry.Game.Shots
+}
+
+// strategyByName builds the named Strategy, defaulting to RandomStrategy for
+// an empty or unrecognized name.
+func strategyByName(name string, minShipLen int) Strategy {
+	switch name {
+	case "hunt":
+		return HuntTargetStrategy{}
+	case "parity":
+		return ParityStrategy{MinShipLen: minShipLen}
+	default:
+		return RandomStrategy{}
+	}
+}
+
+// minFleetLen returns the shortest hull length among types, used to seed
+// ParityStrategy.MinShipLen.
+func minFleetLen(types []ShipType) int {
+	min := 0
+	for _, t := range types {
+		length := StandardFleet[t]
+		if min == 0 || length < min {
+			min = length
+		}
+	}
+	if min == 0 {
+		min = 2
+	}
+	return min
+}
+
+// runBotBenchmark plays trials fresh games per strategy and prints the
+// average number of shots each took to sink the whole fleet, for comparing
+// strategies headlessly.
+func runBotBenchmark(boardSize, armorPerCell, trials int) {
+	minLen := minFleetLen(defaultFleetTypes)
+	strategies := []struct {
+		name     string
+		strategy Strategy
+	}{
+		{"random", RandomStrategy{}},
+		{"hunt", HuntTargetStrategy{}},
+		{"parity", ParityStrategy{MinShipLen: minLen}},
+	}
+
+	for _, s := range strategies {
+		total := 0
+		for i := 0; i < trials; i++ {
+			board := NewBoard(boardSize)
+			fleet, err := NewPlacer(board, armorPerCell).PlaceRandom(defaultFleetTypes)
+			if err != nil {
+				fmt.Printf("%s: placement failed: %v\n", s.name, err)
+				return
+			}
+			game := NewGame(board, fleet)
+			total += NewAIPlayer(s.name, s.strategy).PlayOut(game)
+		}
+		fmt.Printf("%-8s avg shots over %d trials: %.2f\n", s.name, trials, float64(total)/float64(trials))
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import "testing"
+
+func boardWithMarks(size int, marks map[[2]int]rune) *Board {
+	b := NewBoard(size)
+	for cell, mark := range marks {
+		b.Cells[cell[0]][cell[1]] = mark
+	}
+	return b
+}
+
+func TestUntriedNeighborsSkipsOutOfBounds(t *testing.T) {
+	view := NewBoardView(NewBoard(3))
+
+	neighbors := view.untriedNeighbors(0, 0)
+
+	for _, n := range neighbors {
+		if !view.inBounds(n[0], n[1]) {
+			t.Errorf("untriedNeighbors(0,0) returned out-of-bounds cell %v", n)
+		}
+	}
+	if len(neighbors) != 2 {
+		t.Fatalf("corner (0,0) has 2 in-bounds neighbors, got %d: %v", len(neighbors), neighbors)
+	}
+}
+
+func TestHuntTargetStrategyTargetsHitNeighbor(t *testing.T) {
+	board := boardWithMarks(5, map[[2]int]rune{{2, 2}: 'H'})
+	view := NewBoardView(board)
+
+	r, c := (HuntTargetStrategy{}).NextShot(view)
+
+	dr, dc := r-2, c-2
+	if !((dr == 0 && (dc == 1 || dc == -1)) || (dc == 0 && (dr == 1 || dr == -1))) {
+		t.Errorf("NextShot() = (%d,%d), want an orthogonal neighbor of (2,2)", r, c)
+	}
+}
+
+func TestHuntTargetStrategyFallsBackToRandom(t *testing.T) {
+	board := NewBoard(3)
+	view := NewBoardView(board)
+
+	r, c := (HuntTargetStrategy{}).NextShot(view)
+
+	if !view.inBounds(r, c) {
+		t.Fatalf("NextShot() = (%d,%d), want an in-bounds cell", r, c)
+	}
+}
+
+func TestParityStrategyBeforeFirstHit(t *testing.T) {
+	board := NewBoard(6)
+	strategy := ParityStrategy{MinShipLen: 2}
+
+	for i := 0; i < 20; i++ {
+		r, c := strategy.NextShot(NewBoardView(board))
+		if (r+c)%2 != 0 {
+			t.Fatalf("NextShot() = (%d,%d), want (r+c) %% 2 == 0", r, c)
+		}
+	}
+}
+
+func TestParityStrategyAfterHitHunts(t *testing.T) {
+	board := boardWithMarks(5, map[[2]int]rune{{2, 2}: 'H'})
+	strategy := ParityStrategy{MinShipLen: 2}
+
+	r, c := strategy.NextShot(NewBoardView(board))
+
+	dr, dc := r-2, c-2
+	if !((dr == 0 && (dc == 1 || dc == -1)) || (dc == 0 && (dr == 1 || dr == -1))) {
+		t.Errorf("NextShot() = (%d,%d), want an orthogonal neighbor of the hit at (2,2)", r, c)
+	}
+}
+
+func TestAIPlayerPlayOutSinksFleet(t *testing.T) {
+	board := NewBoard(4)
+	fleet, err := NewPlacer(board, 0).PlaceRandom([]ShipType{Destroyer})
+	if err != nil {
+		t.Fatalf("PlaceRandom: %v", err)
+	}
+	game := NewGame(board, fleet)
+
+	shots := NewAIPlayer("hunt", HuntTargetStrategy{}).PlayOut(game)
+
+	if !game.Over {
+		t.Fatal("PlayOut returned with the game not over")
+	}
+	if shots <= 0 || shots > board.Size*board.Size {
+		t.Errorf("PlayOut took %d shots on a %dx%d board, want 1..%d", shots, board.Size, board.Size, board.Size*board.Size)
+	}
+}
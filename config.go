@@ -0,0 +1,72 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+// Config holds the tunable settings for a battleship server, loadable from a
+// JSON file so multiple tuned instances can run side by side without long
+// flag strings. Fields left at their zero value are filled in from the
+// flag defaults in main.
+type Config struct {
+	ListenAddr         string     `json:"listen_addr"`
+	BoardSize          int        `json:"board_size"`
+	DefaultFleet       []ShipType `json:"default_fleet"`
+	ArmorDefaults      int        `json:"armor_defaults"`
+	MaxGames           int        `json:"max_games"`
+	TurnTimeoutSeconds int        `json:"turn_timeout_seconds"`
+	EnablePprof        bool       `json:"enable_pprof"`
+	LogLevel           string     `json:"log_level"`
+}
+
+// DefaultConfigPath returns ~/.config/battleship/config.json for the
+// current user.
+func DefaultConfigPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("resolving home dir: %w", err)
+	}
+	return filepath.Join(home, ".config", "battleship", "config.json"), nil
+}
+
+// LoadConfig reads and parses the Config at path. A missing file is not an
+// error: it returns a zero Config so callers can fall through to flag
+// defaults.
+func LoadConfig(path string) (Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return Config{}, nil
+		}
+		return Config{}, fmt.Errorf("reading config %s: %w", path, err)
+	}
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return Config{}, fmt.Errorf("parsing config %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// currentLogLevel gates debugf. It defaults to "info", meaning debug
+// messages are dropped unless LogLevel is explicitly set to "debug".
+var currentLogLevel = "info"
+
+// SetLogLevel changes the level debugf filters against. An empty level
+// leaves the default in place.
+func SetLogLevel(level string) {
+	if level != "" {
+		currentLogLevel = level
+	}
+}
+
+// debugf logs format like log.Printf, but only when the configured log
+// level is "debug".
+func debugf(format string, args ...any) {
+	if currentLogLevel == "debug" {
+		log.Printf("debug: "+format, args...)
+	}
+}
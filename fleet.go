@@ -0,0 +1,263 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// ShipType identifies one of the standard Battleship hull classes.
+type ShipType string
+
+const (
+	Carrier    ShipType = "carrier"
+	Battleship ShipType = "battleship"
+	Cruiser    ShipType = "cruiser"
+	Submarine  ShipType = "submarine"
+	Destroyer  ShipType = "destroyer"
+)
+
+// StandardFleet maps each hull class to the number of cells it occupies.
+var StandardFleet = map[ShipType]int{
+	Carrier:    5,
+	Battleship: 4,
+	Cruiser:    3,
+	Submarine:  3,
+	Destroyer:  2,
+}
+
+// defaultFleetTypes is the standard five-ship loadout used when a game
+// doesn't specify its own fleet composition.
+var defaultFleetTypes = []ShipType{Carrier, Battleship, Cruiser, Submarine, Destroyer}
+
+// Orientation is the axis a ship is laid out along.
+type Orientation int
+
+const (
+	Horizontal Orientation = iota
+	Vertical
+)
+
+// shipCell is one occupied square of a ship, tracking its own hit points so
+// that Game.TakeShot can wound a ship cell-by-cell.
+type shipCell struct {
+	Row, Col int
+	hp       int
+}
+
+// FleetShip is a ship spanning one or more contiguous cells. Each cell has
+// its own hp (1 + armor), so a ship is only sunk once every cell has been
+// reduced to zero.
+type FleetShip struct {
+	id    int
+	typ   ShipType
+	cells []shipCell
+}
+
+// NewFleetShip lays a ship of typ out from (row, col) in the given
+// orientation, with armor extra hit points on every cell.
+func NewFleetShip(id int, typ ShipType, row, col int, orientation Orientation, armor int) *FleetShip {
+	length := StandardFleet[typ]
+	cells := make([]shipCell, length)
+	for i := 0; i < length; i++ {
+		r, c := row, col
+		if orientation == Horizontal {
+			c += i
+		} else {
+			r += i
+		}
+		cells[i] = shipCell{Row: r, Col: c, hp: 1 + armor}
+	}
+	return &FleetShip{id: id, typ: typ, cells: cells}
+}
+
+func (s *FleetShip) ID() int        { return s.id }
+func (s *FleetShip) Type() ShipType { return s.typ }
+
+// Occupies reports whether the ship has a (possibly already-sunk) cell at (r, c).
+func (s *FleetShip) Occupies(r, c int) bool {
+	for _, cell := range s.cells {
+		if cell.Row == r && cell.Col == c {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *FleetShip) Alive() bool {
+	for _, cell := range s.cells {
+		if cell.hp > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// TakeHit wounds the cell at (r, c), returning whether the shot connected at
+// all and whether it sank the whole ship.
+func (s *FleetShip) TakeHit(r, c int) (hit bool, sunk bool) {
+	for i := range s.cells {
+		cell := &s.cells[i]
+		if cell.Row != r || cell.Col != c {
+			continue
+		}
+		if cell.hp <= 0 {
+			return true, !s.Alive()
+		}
+		cell.hp--
+		return true, !s.Alive()
+	}
+	return false, false
+}
+
+// ShipSnapshot is the on-disk representation of a FleetShip, used by Store
+// to save and restore in-flight games.
+type ShipSnapshot struct {
+	ID    int        `json:"id"`
+	Type  ShipType   `json:"type"`
+	Cells []CellSnap `json:"cells"`
+}
+
+// CellSnap is the on-disk representation of one shipCell.
+type CellSnap struct {
+	Row int `json:"row"`
+	Col int `json:"col"`
+	HP  int `json:"hp"`
+}
+
+// Snapshot captures s's current hit points for persistence.
+func (s *FleetShip) Snapshot() ShipSnapshot {
+	snap := ShipSnapshot{ID: s.id, Type: s.typ, Cells: make([]CellSnap, len(s.cells))}
+	for i, cell := range s.cells {
+		snap.Cells[i] = CellSnap{Row: cell.Row, Col: cell.Col, HP: cell.hp}
+	}
+	return snap
+}
+
+// RestoreFleetShip rebuilds a FleetShip from a previously taken Snapshot.
+func RestoreFleetShip(snap ShipSnapshot) *FleetShip {
+	cells := make([]shipCell, len(snap.Cells))
+	for i, c := range snap.Cells {
+		cells[i] = shipCell{Row: c.Row, Col: c.Col, hp: c.HP}
+	}
+	return &FleetShip{id: snap.ID, typ: snap.Type, cells: cells}
+}
+
+// Fleet owns every ship belonging to one side of the board.
+type Fleet struct {
+	Ships []*FleetShip
+}
+
+func NewFleet() *Fleet {
+	return &Fleet{}
+}
+
+func (f *Fleet) Add(s *FleetShip) {
+	f.Ships = append(f.Ships, s)
+}
+
+// ShipAt returns the ship occupying (r, c), if any.
+func (f *Fleet) ShipAt(r, c int) (*FleetShip, bool) {
+	for _, s := range f.Ships {
+		if s.Occupies(r, c) {
+			return s, true
+		}
+	}
+	return nil, false
+}
+
+// Alive reports whether any ship in the fleet still has an unsunk cell.
+func (f *Fleet) Alive() bool {
+	for _, s := range f.Ships {
+		if s.Alive() {
+			return true
+		}
+	}
+	return false
+}
+
+// Snapshot captures every ship in the fleet for persistence.
+func (f *Fleet) Snapshot() []ShipSnapshot {
+	snaps := make([]ShipSnapshot, len(f.Ships))
+	for i, s := range f.Ships {
+		snaps[i] = s.Snapshot()
+	}
+	return snaps
+}
+
+// RestoreFleet rebuilds a Fleet from ship snapshots taken by Fleet.Snapshot.
+func RestoreFleet(snaps []ShipSnapshot) *Fleet {
+	fleet := NewFleet()
+	for _, snap := range snaps {
+		fleet.Add(RestoreFleetShip(snap))
+	}
+	return fleet
+}
+
+// Placer lays out a fleet on a board without overlaps or out-of-bounds cells.
+type Placer struct {
+	board *Board
+	armor int
+}
+
+func NewPlacer(board *Board, armor int) *Placer {
+	return &Placer{board: board, armor: armor}
+}
+
+// PlaceRandom scatters one ship per entry of types, each at a random
+// position and orientation, retrying until every ship clears the board
+// without overlapping an earlier one.
+func (p *Placer) PlaceRandom(types []ShipType) (*Fleet, error) {
+	fleet := NewFleet()
+	for i, typ := range types {
+		ship, err := p.placeOne(fleet, i+1, typ)
+		if err != nil {
+			return nil, err
+		}
+		fleet.Add(ship)
+	}
+	return fleet, nil
+}
+
+func (p *Placer) placeOne(fleet *Fleet, id int, typ ShipType) (*FleetShip, error) {
+	const maxAttempts = 500
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		orientation := Orientation(rand.Intn(2))
+		row := rand.Intn(p.board.Size)
+		col := rand.Intn(p.board.Size)
+		ship := NewFleetShip(id, typ, row, col, orientation, p.armor)
+		if p.fits(fleet, ship) {
+			return ship, nil
+		}
+	}
+	return nil, fmt.Errorf("could not place %s after %d attempts", typ, maxAttempts)
+}
+
+func (p *Placer) fits(fleet *Fleet, ship *FleetShip) bool {
+	for _, cell := range ship.cells {
+		if !p.board.InBounds(cell.Row, cell.Col) {
+			return false
+		}
+		if _, occupied := fleet.ShipAt(cell.Row, cell.Col); occupied {
+			return false
+		}
+	}
+	return true
+}
+
+// PlaceExplicit places ships at caller-given positions, validating bounds
+// and overlap the same way PlaceRandom does.
+func (p *Placer) PlaceExplicit(placements []struct {
+	Type        ShipType
+	Row, Col    int
+	Orientation Orientation
+}) (*Fleet, error) {
+	fleet := NewFleet()
+	for i, pl := range placements {
+		ship := NewFleetShip(i+1, pl.Type, pl.Row, pl.Col, pl.Orientation, p.armor)
+		if !p.fits(fleet, ship) {
+			return nil, fmt.Errorf("ship %d (%s) overlaps or is out of bounds", i+1, pl.Type)
+		}
+		fleet.Add(ship)
+	}
+	return fleet, nil
+}
@@ -0,0 +1,124 @@
+package main
+
+import "testing"
+
+func TestFleetShipTakeHit(t *testing.T) {
+	tests := []struct {
+		name     string
+		typ      ShipType
+		armor    int
+		shots    [][2]int
+		wantHit  []bool
+		wantSunk []bool
+	}{
+		{
+			name:     "destroyer sinks after both cells hit",
+			typ:      Destroyer,
+			armor:    0,
+			shots:    [][2]int{{0, 0}, {0, 1}},
+			wantHit:  []bool{true, true},
+			wantSunk: []bool{false, true},
+		},
+		{
+			name:     "armored cell needs two hits to sink",
+			typ:      Destroyer,
+			armor:    1,
+			shots:    [][2]int{{0, 0}, {0, 0}, {0, 1}, {0, 1}},
+			wantHit:  []bool{true, true, true, true},
+			wantSunk: []bool{false, false, false, true},
+		},
+		{
+			name:     "miss reports no hit",
+			typ:      Destroyer,
+			armor:    0,
+			shots:    [][2]int{{5, 5}},
+			wantHit:  []bool{false},
+			wantSunk: []bool{false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ship := NewFleetShip(1, tt.typ, 0, 0, Horizontal, tt.armor)
+			for i, shot := range tt.shots {
+				hit, sunk := ship.TakeHit(shot[0], shot[1])
+				if hit != tt.wantHit[i] {
+					t.Errorf("shot %d: hit = %v, want %v", i, hit, tt.wantHit[i])
+				}
+				if sunk != tt.wantSunk[i] {
+					t.Errorf("shot %d: sunk = %v, want %v", i, sunk, tt.wantSunk[i])
+				}
+			}
+		})
+	}
+}
+
+func TestFleetShipSnapshotRoundTrip(t *testing.T) {
+	ship := NewFleetShip(3, Cruiser, 1, 2, Vertical, 2)
+	ship.TakeHit(1, 2)
+
+	restored := RestoreFleetShip(ship.Snapshot())
+
+	if restored.ID() != ship.ID() || restored.Type() != ship.Type() {
+		t.Fatalf("restored ship = %+v, want id=%d type=%s", restored, ship.ID(), ship.Type())
+	}
+	if restored.Alive() != ship.Alive() {
+		t.Errorf("restored.Alive() = %v, want %v", restored.Alive(), ship.Alive())
+	}
+	for _, cell := range []struct{ r, c int }{{1, 2}, {2, 2}, {3, 2}} {
+		if !restored.Occupies(cell.r, cell.c) {
+			t.Errorf("restored ship does not occupy (%d,%d)", cell.r, cell.c)
+		}
+	}
+	// The already-hit cell should still need one more hit after restoring,
+	// since armor was 2 and only one hit was taken before the snapshot.
+	if hit, sunk := restored.TakeHit(1, 2); !hit || sunk {
+		t.Errorf("restored.TakeHit(1,2) = (%v, %v), want (true, false)", hit, sunk)
+	}
+}
+
+func TestPlacerPlaceRandomNoOverlap(t *testing.T) {
+	board := NewBoard(10)
+	fleet, err := NewPlacer(board, 0).PlaceRandom(defaultFleetTypes)
+	if err != nil {
+		t.Fatalf("PlaceRandom: %v", err)
+	}
+	if len(fleet.Ships) != len(defaultFleetTypes) {
+		t.Fatalf("got %d ships, want %d", len(fleet.Ships), len(defaultFleetTypes))
+	}
+
+	seen := make(map[[2]int]int)
+	for _, ship := range fleet.Ships {
+		for r := 0; r < board.Size; r++ {
+			for c := 0; c < board.Size; c++ {
+				if !ship.Occupies(r, c) {
+					continue
+				}
+				if !board.InBounds(r, c) {
+					t.Errorf("ship %d occupies out-of-bounds cell (%d,%d)", ship.ID(), r, c)
+				}
+				seen[[2]int{r, c}]++
+			}
+		}
+	}
+	for cell, count := range seen {
+		if count > 1 {
+			t.Errorf("cell %v occupied by %d ships, want at most 1", cell, count)
+		}
+	}
+}
+
+func TestPlacerPlaceExplicitRejectsOverlap(t *testing.T) {
+	board := NewBoard(5)
+	placements := []struct {
+		Type        ShipType
+		Row, Col    int
+		Orientation Orientation
+	}{
+		{Destroyer, 0, 0, Horizontal},
+		{Destroyer, 0, 1, Horizontal},
+	}
+	if _, err := NewPlacer(board, 0).PlaceExplicit(placements); err == nil {
+		t.Fatal("PlaceExplicit with overlapping ships: got nil error, want one")
+	}
+}
@@ -0,0 +1,372 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// GameConfig describes the single-player game a client wants /game/start to
+// create.
+type GameConfig struct {
+	BoardSize  int        `json:"board_size"`
+	Armor      int        `json:"armor"`
+	Fleet      []ShipType `json:"fleet"`
+	Mode       string     `json:"mode"`
+	MaxPlayers int        `json:"max_players"`
+	Name       string     `json:"name"`
+}
+
+// GameEntry is one game tracked by the Lobby, along with the bookkeeping
+// needed to answer /game/stats.
+type GameEntry struct {
+	ID        string     `json:"id"`
+	Name      string     `json:"name"`
+	Config    GameConfig `json:"config"`
+	Game      *Game      `json:"-"`
+	Stopped   bool       `json:"stopped"`
+	StartedAt time.Time  `json:"started_at"`
+	EndedAt   time.Time  `json:"ended_at,omitempty"`
+
+	// mu serializes access to Game for this entry. net/http runs one
+	// goroutine per request, so two concurrent /shot requests for the
+	// same game id would otherwise race on the Fleet/Board mutations
+	// inside Game.TakeShot.
+	mu sync.Mutex
+}
+
+// GameStats summarizes one game's progress for /game/stats/{id}.
+type GameStats struct {
+	ID       string        `json:"id"`
+	Shots    int           `json:"shots"`
+	Hits     int           `json:"hits"`
+	Misses   int           `json:"misses"`
+	Over     bool          `json:"over"`
+	Duration time.Duration `json:"duration_ns"`
+	Winner   string        `json:"winner,omitempty"`
+}
+
+// Lobby is the process-wide registry of single-player games, replacing the
+// old single global *Game in main. Every handler takes the game id it wants
+// out of the registry instead of closing over one shared value. If Store is
+// set, every shot is snapshotted to it so a crashed server can resume.
+type Lobby struct {
+	mu       sync.RWMutex
+	games    map[string]*GameEntry
+	nextID   int
+	Store    Store
+	MaxGames int
+}
+
+func NewLobby() *Lobby {
+	return &Lobby{games: make(map[string]*GameEntry)}
+}
+
+// NewLobbyWithStore creates a Lobby that snapshots every shot to store and
+// restores any games already on disk.
+func NewLobbyWithStore(store Store) (*Lobby, error) {
+	l := &Lobby{games: make(map[string]*GameEntry), Store: store}
+	ids, err := store.List()
+	if err != nil {
+		return nil, fmt.Errorf("listing saved games: %w", err)
+	}
+	for _, id := range ids {
+		entry, err := LoadGame(store, id)
+		if err != nil {
+			log.Printf("lobby: skipping unloadable game %s: %v", id, err)
+			continue
+		}
+		l.games[id] = entry
+		if n, err := strconv.Atoi(strings.TrimPrefix(id, "g")); err == nil && n > l.nextID {
+			l.nextID = n
+		}
+	}
+	return l, nil
+}
+
+// Start creates a game from cfg, filling in sane defaults, and registers it
+// under a server-generated id.
+func (l *Lobby) Start(cfg GameConfig) (*GameEntry, error) {
+	if cfg.BoardSize <= 0 {
+		cfg.BoardSize = 5
+	}
+	if len(cfg.Fleet) == 0 {
+		cfg.Fleet = defaultFleetTypes
+	}
+	if cfg.MaxPlayers <= 0 {
+		cfg.MaxPlayers = 1
+	}
+	if cfg.Mode == "" {
+		cfg.Mode = "single"
+	}
+
+	board := NewBoard(cfg.BoardSize)
+	fleet, err := NewPlacer(board, cfg.Armor).PlaceRandom(cfg.Fleet)
+	if err != nil {
+		return nil, fmt.Errorf("placing fleet: %w", err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.MaxGames > 0 {
+		active := 0
+		for _, e := range l.games {
+			if !e.Stopped && !e.Game.Over {
+				active++
+			}
+		}
+		if active >= l.MaxGames {
+			return nil, fmt.Errorf("lobby is full: %d games already running", l.MaxGames)
+		}
+	}
+	l.nextID++
+	id := "g" + strconv.Itoa(l.nextID)
+	entry := &GameEntry{
+		ID:        id,
+		Name:      cfg.Name,
+		Config:    cfg,
+		Game:      NewGame(board, fleet),
+		StartedAt: time.Now(),
+	}
+	l.games[id] = entry
+	l.save(entry)
+	debugf("lobby: started game %s (size=%d armor=%d)", id, cfg.BoardSize, cfg.Armor)
+	return entry, nil
+}
+
+// save snapshots entry to l.Store, if one is configured. Errors are logged
+// rather than returned since a failed snapshot shouldn't fail the shot that
+// triggered it.
+func (l *Lobby) save(entry *GameEntry) {
+	if l.Store == nil {
+		return
+	}
+	if err := l.Store.Save(entry.snapshot()); err != nil {
+		log.Printf("lobby: snapshotting game %s: %v", entry.ID, err)
+	}
+}
+
+// TakeShot fires a shot at id's game and snapshots the resulting state.
+func (l *Lobby) TakeShot(id string, r, c int) (ShotResult, error) {
+	l.mu.Lock()
+	entry, ok := l.games[id]
+	l.mu.Unlock()
+	if !ok {
+		return ShotResult{}, fmt.Errorf("no such game: %s", id)
+	}
+
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+
+	res, err := entry.Game.TakeShot(r, c)
+	if err != nil {
+		return ShotResult{}, err
+	}
+	debugf("lobby: game %s shot (%d,%d) hit=%v sunk=%v", id, r, c, res.Hit, res.Sunk)
+	if entry.Game.Over && entry.EndedAt.IsZero() {
+		entry.EndedAt = time.Now()
+	}
+	l.save(entry)
+	return res, nil
+}
+
+func (l *Lobby) Get(id string) (*GameEntry, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	e, ok := l.games[id]
+	return e, ok
+}
+
+// List returns every known game, active or stopped.
+func (l *Lobby) List() []*GameEntry {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	out := make([]*GameEntry, 0, len(l.games))
+	for _, e := range l.games {
+		out = append(out, e)
+	}
+	return out
+}
+
+// Stop marks a game stopped so it no longer accepts shots. It stays in the
+// registry so /game/stats and /game/list can still report on it.
+func (l *Lobby) Stop(id string) error {
+	l.mu.Lock()
+	e, ok := l.games[id]
+	if !ok {
+		l.mu.Unlock()
+		return fmt.Errorf("no such game: %s", id)
+	}
+	e.Stopped = true
+	if e.EndedAt.IsZero() {
+		e.EndedAt = time.Now()
+	}
+	l.mu.Unlock()
+	l.save(e)
+	return nil
+}
+
+// Stats reports shot counts for id, deriving hits/misses from the board
+// marks TakeShot already leaves behind.
+func (l *Lobby) Stats(id string) (GameStats, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	e, ok := l.games[id]
+	if !ok {
+		return GameStats{}, fmt.Errorf("no such game: %s", id)
+	}
+	stats := GameStats{ID: id, Shots: e.Game.Shots, Over: e.Game.Over}
+	for _, row := range e.Game.Board.Cells {
+		for _, c := range row {
+			switch c {
+			case 'H', 'X':
+				stats.Hits++
+			case 'o':
+				stats.Misses++
+			}
+		}
+	}
+	if e.Game.Over {
+		stats.Winner = "player"
+		if !e.EndedAt.IsZero() {
+			stats.Duration = e.EndedAt.Sub(e.StartedAt)
+		}
+	}
+	return stats, nil
+}
+
+// Prune deletes every stopped or finished game whose EndedAt is older than
+// ttl, both from the registry and from Store if one is configured. It's
+// meant to be called periodically from a background goroutine.
+func (l *Lobby) Prune(ttl time.Duration) {
+	cutoff := time.Now().Add(-ttl)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for id, e := range l.games {
+		finished := e.Stopped || e.Game.Over
+		if !finished || e.EndedAt.IsZero() || e.EndedAt.After(cutoff) {
+			continue
+		}
+		delete(l.games, id)
+		if l.Store != nil {
+			if err := l.Store.Delete(id); err != nil {
+				log.Printf("lobby: pruning game %s: %v", id, err)
+			}
+		}
+	}
+}
+
+// PruneLoop runs Prune every interval until stop is closed.
+func (l *Lobby) PruneLoop(ttl, interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			l.Prune(ttl)
+		case <-stop:
+			return
+		}
+	}
+}
+
+// registerLobbyRoutes wires /game/start, /game/list, /game/join/{id},
+// /game/stats/{id}, /game/stop/{id} and /game/bot into mux. These all
+// operate on single-player Lobby games only; the two-player Match type
+// registered by registerMatchRoutes has its own /match/new and /ws/{id}
+// endpoints and is not tracked in this registry, so it won't show up in
+// /game/list or /game/stats and has no bot support.
+func registerLobbyRoutes(mux *http.ServeMux, lobby *Lobby) {
+	mux.HandleFunc("/game/start", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "POST required", http.StatusMethodNotAllowed)
+			return
+		}
+		var cfg GameConfig
+		if err := json.NewDecoder(r.Body).Decode(&cfg); err != nil {
+			http.Error(w, "invalid config: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		entry, err := lobby.Start(cfg)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]string{"id": entry.ID})
+	})
+
+	mux.HandleFunc("/game/list", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(lobby.List())
+	})
+
+	mux.HandleFunc("/game/join/", func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimPrefix(r.URL.Path, "/game/join/")
+		entry, ok := lobby.Get(id)
+		if !ok {
+			http.Error(w, "no such game", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"id":     entry.ID,
+			"name":   entry.Name,
+			"config": entry.Config,
+			"board":  entry.Game.Render(false),
+			"over":   entry.Game.Over,
+		})
+	})
+
+	mux.HandleFunc("/game/stats/", func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimPrefix(r.URL.Path, "/game/stats/")
+		stats, err := lobby.Stats(id)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(stats)
+	})
+
+	mux.HandleFunc("/game/bot", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "POST required", http.StatusMethodNotAllowed)
+			return
+		}
+		var req struct {
+			ID       string `json:"id"`
+			Strategy string `json:"strategy"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid request: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		entry, ok := lobby.Get(req.ID)
+		if !ok {
+			http.Error(w, "no such game", http.StatusNotFound)
+			return
+		}
+		strategy := strategyByName(req.Strategy, minFleetLen(entry.Config.Fleet))
+		bot := NewAIPlayer(req.Strategy, strategy)
+		go bot.PlayOutLobby(lobby, entry)
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]bool{"started": true})
+	})
+
+	mux.HandleFunc("/game/stop/", func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimPrefix(r.URL.Path, "/game/stop/")
+		if err := lobby.Stop(id); err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]bool{"stopped": true})
+	})
+}
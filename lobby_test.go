@@ -0,0 +1,71 @@
+package main
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestLobbyTakeShotConcurrentSameGame(t *testing.T) {
+	// A fully-armored Carrier can absorb every shot on the board without
+	// the game ever ending, so every goroutine's shot is expected to land
+	// and increment Game.Shots exactly once.
+	lobby := NewLobby()
+	entry, err := lobby.Start(GameConfig{BoardSize: 8, Armor: 64, Fleet: []ShipType{Carrier}})
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for r := 0; r < 8; r++ {
+		for c := 0; c < 8; c++ {
+			wg.Add(1)
+			go func(r, c int) {
+				defer wg.Done()
+				_, _ = lobby.TakeShot(entry.ID, r, c)
+			}(r, c)
+		}
+	}
+	wg.Wait()
+
+	if entry.Game.Shots != 64 {
+		t.Errorf("Game.Shots = %d, want 64 (every concurrent shot should register exactly once)", entry.Game.Shots)
+	}
+}
+
+func TestLobbyStartRejectsOverMaxGames(t *testing.T) {
+	lobby := NewLobby()
+	lobby.MaxGames = 1
+
+	if _, err := lobby.Start(GameConfig{BoardSize: 4, Fleet: []ShipType{Destroyer}}); err != nil {
+		t.Fatalf("first Start: %v", err)
+	}
+	if _, err := lobby.Start(GameConfig{BoardSize: 4, Fleet: []ShipType{Destroyer}}); err == nil {
+		t.Fatal("second Start with MaxGames=1: got nil error, want lobby-full error")
+	}
+}
+
+func TestLobbyStartConcurrentRespectsMaxGames(t *testing.T) {
+	lobby := NewLobby()
+	lobby.MaxGames = 3
+
+	const attempts = 20
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	started := 0
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := lobby.Start(GameConfig{BoardSize: 4, Fleet: []ShipType{Destroyer}}); err == nil {
+				mu.Lock()
+				started++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if started != lobby.MaxGames {
+		t.Errorf("started = %d games, want exactly MaxGames=%d", started, lobby.MaxGames)
+	}
+}
@@ -0,0 +1,350 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// matchMessage is the JSON envelope exchanged over a match's WebSocket. Only
+// the fields relevant to Type are populated.
+type matchMessage struct {
+	Type   string          `json:"type"`
+	Name   string          `json:"name,omitempty"`
+	Ships  []shipPlacement `json:"ships,omitempty"`
+	Row    int             `json:"row,omitempty"`
+	Col    int             `json:"col,omitempty"`
+	Player int             `json:"player,omitempty"`
+	Result *ShotResult     `json:"result,omitempty"`
+	Winner int             `json:"winner,omitempty"`
+	Reason string          `json:"reason,omitempty"`
+	Error  string          `json:"error,omitempty"`
+}
+
+// shipPlacement is how a client describes one ship of its fleet in a "place"
+// message.
+type shipPlacement struct {
+	Type        ShipType    `json:"type"`
+	Row         int         `json:"row"`
+	Col         int         `json:"col"`
+	Orientation Orientation `json:"orientation"`
+}
+
+// MatchPlayer is one side of a Match: a connected (or not-yet-connected)
+// opponent with their own board and fleet.
+type MatchPlayer struct {
+	Player
+	Board *Board
+	Fleet *Fleet
+	conn  *wsConn
+	ready bool
+}
+
+// Match is a two-player networked game. Each player shoots at the other's
+// board; TakeShot is applied to the opponent's Fleet, mirroring Game's
+// single-board semantics but doubled.
+type Match struct {
+	ID          string
+	BoardSize   int
+	Armor       int
+	Players     [2]*MatchPlayer
+	Turn        int
+	Over        bool
+	TurnTimeout time.Duration
+
+	mu    sync.Mutex
+	timer *time.Timer
+}
+
+// NewMatch creates an empty two-player match; players attach by connecting
+// to /ws/{id} and neither board has a fleet until both place one. A
+// turnTimeout of 0 disables the turn clock.
+func NewMatch(id string, boardSize, armor int, turnTimeout time.Duration) *Match {
+	return &Match{
+		ID:          id,
+		BoardSize:   boardSize,
+		Armor:       armor,
+		TurnTimeout: turnTimeout,
+		Players: [2]*MatchPlayer{
+			{Board: NewBoard(boardSize)},
+			{Board: NewBoard(boardSize)},
+		},
+	}
+}
+
+// resetTurnTimer restarts the turn clock for whoever m.Turn currently is. It
+// must be called with m.mu held. If no timeout is configured this is a
+// no-op.
+func (m *Match) resetTurnTimer() {
+	if m.timer != nil {
+		m.timer.Stop()
+		m.timer = nil
+	}
+	if m.TurnTimeout <= 0 {
+		return
+	}
+	turn := m.Turn
+	m.timer = time.AfterFunc(m.TurnTimeout, func() {
+		m.forfeitOnTimeout(turn)
+	})
+}
+
+// forfeitOnTimeout ends the match in the opponent's favor if slot still
+// hasn't moved by the time its turn clock expires.
+func (m *Match) forfeitOnTimeout(slot int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.Over || m.Turn != slot {
+		return
+	}
+	m.Over = true
+	m.broadcast(matchMessage{Type: "game_over", Winner: m.opponent(slot), Reason: "turn timeout"})
+}
+
+// send writes msg to a single player's socket, if connected.
+func (m *Match) send(slot int, msg matchMessage) {
+	p := m.Players[slot]
+	if p == nil || p.conn == nil {
+		return
+	}
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return
+	}
+	if err := p.conn.WriteMessage(data); err != nil {
+		log.Printf("match %s: write to player %d: %v", m.ID, slot, err)
+	}
+}
+
+// broadcast writes msg to both players.
+func (m *Match) broadcast(msg matchMessage) {
+	m.send(0, msg)
+	m.send(1, msg)
+}
+
+func (m *Match) opponent(slot int) int { return 1 - slot }
+
+// attach binds slot to conn, completing that player's join.
+func (m *Match) attach(slot int, name string, conn *wsConn) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.Players[slot].conn != nil {
+		return fmt.Errorf("player slot %d already connected", slot)
+	}
+	m.Players[slot].Name = name
+	m.Players[slot].conn = conn
+	return nil
+}
+
+// handlePlace validates and installs slot's fleet, starting the match once
+// both players have placed.
+func (m *Match) handlePlace(slot int, ships []shipPlacement) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.Over {
+		return fmt.Errorf("match is already over")
+	}
+	if m.Players[slot].ready {
+		return fmt.Errorf("slot %d already placed a fleet", slot)
+	}
+
+	placer := NewPlacer(m.Players[slot].Board, m.Armor)
+	placements := make([]struct {
+		Type        ShipType
+		Row, Col    int
+		Orientation Orientation
+	}, len(ships))
+	for i, s := range ships {
+		placements[i] = struct {
+			Type        ShipType
+			Row, Col    int
+			Orientation Orientation
+		}{s.Type, s.Row, s.Col, s.Orientation}
+	}
+	fleet, err := placer.PlaceExplicit(placements)
+	if err != nil {
+		return err
+	}
+	m.Players[slot].Fleet = fleet
+	m.Players[slot].ready = true
+
+	if m.Players[0].ready && m.Players[1].ready {
+		m.resetTurnTimer()
+		m.broadcast(matchMessage{Type: "turn", Player: m.Turn})
+	}
+	return nil
+}
+
+// handleShot fires slot's shot at the opponent's board and advances turn.
+func (m *Match) handleShot(slot, row, col int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !(m.Players[0].ready && m.Players[1].ready) {
+		return fmt.Errorf("match has not started")
+	}
+	if m.Over {
+		return fmt.Errorf("match is already over")
+	}
+	if slot != m.Turn {
+		return fmt.Errorf("not your turn")
+	}
+
+	opp := m.Players[m.opponent(slot)]
+	game := &Game{Board: opp.Board, Fleet: opp.Fleet}
+	res, err := game.TakeShot(row, col)
+	if err != nil {
+		return err
+	}
+	m.broadcast(matchMessage{Type: "shot_result", Player: slot, Row: row, Col: col, Result: &res})
+
+	if game.Over {
+		m.Over = true
+		if m.timer != nil {
+			m.timer.Stop()
+		}
+		m.broadcast(matchMessage{Type: "game_over", Winner: slot})
+		return nil
+	}
+
+	m.Turn = m.opponent(slot)
+	m.resetTurnTimer()
+	m.broadcast(matchMessage{Type: "turn", Player: m.Turn})
+	return nil
+}
+
+// disconnect marks the match over and tells the remaining player they won by
+// forfeit.
+func (m *Match) disconnect(slot int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.Over {
+		return
+	}
+	m.Over = true
+	if m.timer != nil {
+		m.timer.Stop()
+	}
+	m.Players[slot].conn = nil
+	m.send(m.opponent(slot), matchMessage{Type: "game_over", Winner: m.opponent(slot), Reason: "opponent disconnected"})
+}
+
+// serveMatchWS upgrades the connection, seats it in the first free slot of
+// the match, and pumps messages until the client disconnects.
+func (m *Match) serveMatchWS(w http.ResponseWriter, r *http.Request) {
+	conn, err := upgradeWebSocket(w, r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer conn.Close()
+
+	slot := -1
+	for i, p := range m.Players {
+		if p.conn == nil {
+			slot = i
+			break
+		}
+	}
+	if slot == -1 {
+		_ = conn.WriteMessage(mustJSON(matchMessage{Type: "join", Error: "match is full"}))
+		return
+	}
+
+	name := r.URL.Query().Get("name")
+	if err := m.attach(slot, name, conn); err != nil {
+		_ = conn.WriteMessage(mustJSON(matchMessage{Type: "join", Error: err.Error()}))
+		return
+	}
+	m.send(slot, matchMessage{Type: "join", Player: slot})
+
+	for {
+		raw, err := conn.ReadMessage()
+		if err != nil {
+			m.disconnect(slot)
+			return
+		}
+		var msg matchMessage
+		if err := json.Unmarshal(raw, &msg); err != nil {
+			continue
+		}
+
+		switch msg.Type {
+		case "place":
+			if err := m.handlePlace(slot, msg.Ships); err != nil {
+				m.send(slot, matchMessage{Type: "place", Error: err.Error()})
+			}
+		case "shot":
+			if err := m.handleShot(slot, msg.Row, msg.Col); err != nil {
+				m.send(slot, matchMessage{Type: "shot", Error: err.Error()})
+			}
+		}
+	}
+}
+
+func mustJSON(msg matchMessage) []byte {
+	data, _ := json.Marshal(msg)
+	return data
+}
+
+// matchIDFromPath extracts {id} from a "/ws/{id}" request path.
+func matchIDFromPath(path string) string {
+	return strings.TrimPrefix(strings.TrimPrefix(path, "/ws/"), "/")
+}
+
+// matchRegistry is the process-wide set of in-flight matches. It is
+// superseded by the Lobby/GameRegistry for single-player games, but
+// multiplayer matches are tracked separately since each owns two boards.
+type matchRegistry struct {
+	mu      sync.Mutex
+	matches map[string]*Match
+	nextID  int
+}
+
+var matches = &matchRegistry{matches: make(map[string]*Match)}
+
+// create allocates a fresh match with a server-generated ID.
+func (r *matchRegistry) create(boardSize, armor int, turnTimeout time.Duration) *Match {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	id := fmt.Sprintf("m%d", r.nextID)
+	m := NewMatch(id, boardSize, armor, turnTimeout)
+	r.matches[id] = m
+	return m
+}
+
+func (r *matchRegistry) get(id string) (*Match, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	m, ok := r.matches[id]
+	return m, ok
+}
+
+// registerMatchRoutes wires the multiplayer endpoints into mux: POST
+// /match/new creates a match and GET /ws/{id} upgrades into it. turnTimeout
+// of 0 leaves matches without a turn clock. Matches live in matchRegistry,
+// separate from the Lobby's single-player games, so they have no bot
+// support and don't appear in /game/list or /game/stats/{id}.
+func registerMatchRoutes(mux *http.ServeMux, boardSize, armor int, turnTimeout time.Duration) {
+	mux.HandleFunc("/match/new", func(w http.ResponseWriter, r *http.Request) {
+		m := matches.create(boardSize, armor, turnTimeout)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]string{"id": m.ID})
+	})
+
+	mux.HandleFunc("/ws/", func(w http.ResponseWriter, r *http.Request) {
+		id := matchIDFromPath(r.URL.Path)
+		m, ok := matches.get(id)
+		if !ok {
+			http.Error(w, "no such match", http.StatusNotFound)
+			return
+		}
+		m.serveMatchWS(w, r)
+	})
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// GameSnapshot is everything needed to restore an in-flight Game: the board
+// marks, every ship's remaining hit points, and the shot counter.
+type GameSnapshot struct {
+	ID        string         `json:"id"`
+	Config    GameConfig     `json:"config"`
+	BoardSize int            `json:"board_size"`
+	Cells     [][]rune       `json:"cells"`
+	Fleet     []ShipSnapshot `json:"fleet"`
+	Shots     int            `json:"shots"`
+	Over      bool           `json:"over"`
+	Stopped   bool           `json:"stopped"`
+	StartedAt time.Time      `json:"started_at"`
+	EndedAt   time.Time      `json:"ended_at,omitempty"`
+}
+
+// Store persists and restores GameSnapshots by id.
+type Store interface {
+	Save(snap GameSnapshot) error
+	Load(id string) (GameSnapshot, error)
+	Delete(id string) error
+	List() ([]string, error)
+}
+
+// FileStore is a Store backed by one JSON file per game in Dir.
+type FileStore struct {
+	Dir string
+}
+
+// NewFileStore creates a FileStore rooted at dir, creating it if necessary.
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("creating store dir: %w", err)
+	}
+	return &FileStore{Dir: dir}, nil
+}
+
+func (s *FileStore) path(id string) string {
+	return filepath.Join(s.Dir, id+".json")
+}
+
+func (s *FileStore) Save(snap GameSnapshot) error {
+	data, err := json.MarshalIndent(snap, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.path(snap.ID), data, 0o644)
+}
+
+func (s *FileStore) Load(id string) (GameSnapshot, error) {
+	data, err := os.ReadFile(s.path(id))
+	if err != nil {
+		return GameSnapshot{}, err
+	}
+	var snap GameSnapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return GameSnapshot{}, err
+	}
+	return snap, nil
+}
+
+func (s *FileStore) Delete(id string) error {
+	err := os.Remove(s.path(id))
+	if err != nil && os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+func (s *FileStore) List() ([]string, error) {
+	entries, err := os.ReadDir(s.Dir)
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for _, e := range entries {
+		if name, ok := strings.CutSuffix(e.Name(), ".json"); ok {
+			ids = append(ids, name)
+		}
+	}
+	return ids, nil
+}
+
+// snapshot builds a GameSnapshot of entry's current state.
+func (e *GameEntry) snapshot() GameSnapshot {
+	return GameSnapshot{
+		ID:        e.ID,
+		Config:    e.Config,
+		BoardSize: e.Game.Board.Size,
+		Cells:     e.Game.Board.Cells,
+		Fleet:     e.Game.Fleet.Snapshot(),
+		Shots:     e.Game.Shots,
+		Over:      e.Game.Over,
+		Stopped:   e.Stopped,
+		StartedAt: e.StartedAt,
+		EndedAt:   e.EndedAt,
+	}
+}
+
+// LoadGame restores a GameEntry from store, letting a restarted server pick
+// up an in-flight match.
+func LoadGame(store Store, id string) (*GameEntry, error) {
+	snap, err := store.Load(id)
+	if err != nil {
+		return nil, fmt.Errorf("loading game %s: %w", id, err)
+	}
+	board := &Board{Size: snap.BoardSize, Cells: snap.Cells}
+	fleet := RestoreFleet(snap.Fleet)
+	game := NewGame(board, fleet)
+	game.Shots = snap.Shots
+	game.Over = snap.Over
+
+	return &GameEntry{
+		ID:        snap.ID,
+		Name:      snap.Config.Name,
+		Config:    snap.Config,
+		Game:      game,
+		Stopped:   snap.Stopped,
+		StartedAt: snap.StartedAt,
+		EndedAt:   snap.EndedAt,
+	}, nil
+}
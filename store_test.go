@@ -0,0 +1,97 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFileStoreSaveLoadRoundTrip(t *testing.T) {
+	store, err := NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileStore: %v", err)
+	}
+
+	board := NewBoard(4)
+	fleet, err := NewPlacer(board, 1).PlaceRandom([]ShipType{Destroyer})
+	if err != nil {
+		t.Fatalf("PlaceRandom: %v", err)
+	}
+	game := NewGame(board, fleet)
+	if _, err := game.TakeShot(0, 0); err != nil {
+		t.Fatalf("TakeShot: %v", err)
+	}
+
+	entry := &GameEntry{
+		ID:        "g1",
+		Name:      "test game",
+		Config:    GameConfig{BoardSize: 4, Armor: 1, Name: "test game"},
+		Game:      game,
+		Stopped:   true,
+		StartedAt: time.Unix(1000, 0).UTC(),
+		EndedAt:   time.Unix(2000, 0).UTC(),
+	}
+
+	if err := store.Save(entry.snapshot()); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	restored, err := LoadGame(store, "g1")
+	if err != nil {
+		t.Fatalf("LoadGame: %v", err)
+	}
+
+	if restored.ID != entry.ID || restored.Name != entry.Name {
+		t.Errorf("restored id/name = %q/%q, want %q/%q", restored.ID, restored.Name, entry.ID, entry.Name)
+	}
+	if restored.Stopped != entry.Stopped {
+		t.Errorf("restored.Stopped = %v, want %v", restored.Stopped, entry.Stopped)
+	}
+	if !restored.StartedAt.Equal(entry.StartedAt) || !restored.EndedAt.Equal(entry.EndedAt) {
+		t.Errorf("restored timestamps = %v/%v, want %v/%v", restored.StartedAt, restored.EndedAt, entry.StartedAt, entry.EndedAt)
+	}
+	if restored.Game.Shots != game.Shots || restored.Game.Over != game.Over {
+		t.Errorf("restored game = (shots=%d over=%v), want (shots=%d over=%v)",
+			restored.Game.Shots, restored.Game.Over, game.Shots, game.Over)
+	}
+	if len(restored.Game.Fleet.Ships) != len(game.Fleet.Ships) {
+		t.Fatalf("restored fleet has %d ships, want %d", len(restored.Game.Fleet.Ships), len(game.Fleet.Ships))
+	}
+}
+
+func TestFileStoreListAndDelete(t *testing.T) {
+	store, err := NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileStore: %v", err)
+	}
+
+	if err := store.Save(GameSnapshot{ID: "g1", BoardSize: 3, Cells: NewBoard(3).Cells}); err != nil {
+		t.Fatalf("Save g1: %v", err)
+	}
+	if err := store.Save(GameSnapshot{ID: "g2", BoardSize: 3, Cells: NewBoard(3).Cells}); err != nil {
+		t.Fatalf("Save g2: %v", err)
+	}
+
+	ids, err := store.List()
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(ids) != 2 {
+		t.Fatalf("List() = %v, want 2 ids", ids)
+	}
+
+	if err := store.Delete("g1"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	ids, err = store.List()
+	if err != nil {
+		t.Fatalf("List after delete: %v", err)
+	}
+	if len(ids) != 1 || ids[0] != "g2" {
+		t.Fatalf("List() after deleting g1 = %v, want [g2]", ids)
+	}
+
+	// Deleting an already-deleted id is not an error.
+	if err := store.Delete("g1"); err != nil {
+		t.Errorf("Delete of missing id: %v, want nil", err)
+	}
+}
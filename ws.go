@@ -0,0 +1,148 @@
+package main
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+)
+
+// websocketGUID is the magic value RFC 6455 uses to derive the
+// Sec-WebSocket-Accept header from the client's Sec-WebSocket-Key.
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+const (
+	wsOpText  = 0x1
+	wsOpClose = 0x8
+)
+
+// wsConn is a minimal RFC 6455 text-frame connection. It supports the
+// unfragmented text frames the battleship client/server protocol needs and
+// deliberately nothing else (no ping/pong, no compression extensions).
+type wsConn struct {
+	conn net.Conn
+	rw   *bufio.ReadWriter
+}
+
+// upgradeWebSocket performs the HTTP -> WebSocket handshake by hand, since
+// this module has no vendored websocket dependency.
+func upgradeWebSocket(w http.ResponseWriter, r *http.Request) (*wsConn, error) {
+	if r.Header.Get("Upgrade") != "websocket" {
+		return nil, fmt.Errorf("missing Upgrade: websocket header")
+	}
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if key == "" {
+		return nil, fmt.Errorf("missing Sec-WebSocket-Key header")
+	}
+
+	hj, ok := w.(http.Hijacker)
+	if !ok {
+		return nil, fmt.Errorf("connection does not support hijacking")
+	}
+	conn, rw, err := hj.Hijack()
+	if err != nil {
+		return nil, fmt.Errorf("hijack: %w", err)
+	}
+
+	sum := sha1.Sum([]byte(key + websocketGUID))
+	accept := base64.StdEncoding.EncodeToString(sum[:])
+
+	resp := "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + accept + "\r\n\r\n"
+	if _, err := rw.WriteString(resp); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if err := rw.Flush(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return &wsConn{conn: conn, rw: rw}, nil
+}
+
+// ReadMessage reads a single unfragmented text frame and returns its payload.
+func (c *wsConn) ReadMessage() ([]byte, error) {
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(c.rw, header); err != nil {
+		return nil, err
+	}
+	opcode := header[0] & 0x0f
+	masked := header[1]&0x80 != 0
+	length := uint64(header[1] & 0x7f)
+
+	switch length {
+	case 126:
+		ext := make([]byte, 2)
+		if _, err := io.ReadFull(c.rw, ext); err != nil {
+			return nil, err
+		}
+		length = uint64(ext[0])<<8 | uint64(ext[1])
+	case 127:
+		ext := make([]byte, 8)
+		if _, err := io.ReadFull(c.rw, ext); err != nil {
+			return nil, err
+		}
+		length = 0
+		for _, b := range ext {
+			length = length<<8 | uint64(b)
+		}
+	}
+
+	var maskKey [4]byte
+	if masked {
+		if _, err := io.ReadFull(c.rw, maskKey[:]); err != nil {
+			return nil, err
+		}
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(c.rw, payload); err != nil {
+		return nil, err
+	}
+	if masked {
+		for i := range payload {
+			payload[i] ^= maskKey[i%4]
+		}
+	}
+
+	if opcode == wsOpClose {
+		return nil, io.EOF
+	}
+	return payload, nil
+}
+
+// WriteMessage sends data as a single unmasked server text frame.
+func (c *wsConn) WriteMessage(data []byte) error {
+	var header []byte
+	n := len(data)
+	switch {
+	case n <= 125:
+		header = []byte{0x80 | wsOpText, byte(n)}
+	case n <= 0xffff:
+		header = []byte{0x80 | wsOpText, 126, byte(n >> 8), byte(n)}
+	default:
+		header = make([]byte, 10)
+		header[0] = 0x80 | wsOpText
+		header[1] = 127
+		for i := 0; i < 8; i++ {
+			header[2+i] = byte(n >> uint(56-8*i))
+		}
+	}
+	if _, err := c.rw.Write(header); err != nil {
+		return err
+	}
+	if _, err := c.rw.Write(data); err != nil {
+		return err
+	}
+	return c.rw.Flush()
+}
+
+func (c *wsConn) Close() error {
+	return c.conn.Close()
+}